@@ -0,0 +1,45 @@
+package snowflake
+
+import "fmt"
+
+/**
+ * NextIDs 一次性生成 n 个ID，只获取一次锁。第一个ID复用 nextLocked，与单个 NextIDSafe
+ * 共享完全一致的时钟回拨处理；后续ID只在内存中推进序列号，只有序列号耗尽需要滚动到
+ * 下一毫秒时才会再次读取时钟，因此常见情况下整批调用只读一次当前时间而不是 n 次，
+ * 相比循环调用 n 次 NextID 既省去了 n-1 次锁竞争，也省去了 n-1 次时钟读取。
+ *
+ * 返回的ID序列满足严格单调递增，且时钟回拨、序列号耗尽时的处理方式与单个 NextIDSafe
+ * 完全一致
+ */
+func (s *SnowFlake) NextIDs(n int) ([]int64, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("snowflake: n must be positive, got %d", n)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	ids := make([]int64, n)
+
+	first, err := s.nextLocked()
+	if err != nil {
+		return nil, err
+	}
+	ids[0] = first
+
+	for i := 1; i < n; i++ {
+		now := s.timestamp
+		s.sequence = (s.sequence + 1) & s.cfg.maxSequence()
+		if s.sequence == 0 {
+			// 同一毫秒的序列数已经用完，必须滚动到下一毫秒，这时才需要再次读取时钟
+			for now <= s.timestamp {
+				now = s.GetNow()
+			}
+			s.timestamp = now
+		}
+
+		ids[i] = s.encode(now)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,50 @@
+package snowflake
+
+import "testing"
+
+func TestNextIDsStrictlyMonotonic(t *testing.T) {
+	sf, err := New(1, 1)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ids, err := sf.NextIDs(5000)
+	if err != nil {
+		t.Fatalf("NextIDs failed: %v", err)
+	}
+	if len(ids) != 5000 {
+		t.Fatalf("len(ids) = %d, want 5000", len(ids))
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("ids[%d]=%d is not strictly greater than ids[%d]=%d", i, ids[i], i-1, ids[i-1])
+		}
+	}
+}
+
+func TestNextIDsRejectsNonPositiveN(t *testing.T) {
+	sf, err := New(1, 1)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, err := sf.NextIDs(0); err == nil {
+		t.Fatal("expected NextIDs(0) to return an error")
+	}
+	if _, err := sf.NextIDs(-1); err == nil {
+		t.Fatal("expected NextIDs(-1) to return an error")
+	}
+}
+
+func TestNextIDsSharesBackwardSemanticsWithNextIDSafe(t *testing.T) {
+	fc := NewFakeClock(10_000)
+	sf, err := New(0, 0, WithClock(fc))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	sf.NextID()
+	fc.Set(9_000) // rollback beyond tolerance
+
+	if _, err := sf.NextIDs(3); err == nil {
+		t.Fatal("expected NextIDs to surface the same backward-clock error as NextIDSafe instead of panicking")
+	}
+}
@@ -0,0 +1,125 @@
+package snowflake
+
+import (
+	"sync"
+	"time"
+)
+
+/**
+ * BufferedSnowFlake 在 SnowFlake 的基础上，用一个后台 goroutine 持续生产 ID 并写入
+ * 带缓冲的 channel，调用方直接从 channel 取值，从而避免每次取号都去抢占内部的 sync.Mutex。
+ * 适合高并发、对取号延迟敏感的场景。
+ */
+
+// produceRetryBackoff 是后台生产者在 NextIDSafe 返回error（例如 wait-queue 策略等待
+// 超时）之后，重试前的等待时间，避免在持续性错误下忙等占满 CPU
+const produceRetryBackoff = 10 * time.Millisecond
+
+type BufferedSnowFlake struct {
+	sf     *SnowFlake
+	ch     chan int64
+	closed chan struct{}
+	done   chan struct{}
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+/**
+ * NewBuffered 创建一个带缓冲的生成器，bufSize 为预生成 ID 的缓冲区大小。opts 会原样
+ * 透传给内部的 New，因此 WithClock、WithClockBackwardStrategy 等选项同样适用于后台
+ * 生产者：选择 wait-queue 或 extension-bit 策略可以让 produce 在时钟回拨时继续重试/
+ * 继续生成而不是让后台 goroutine 直接 panic 拖垮整个进程；即便如此，produce 仍可能
+ * 持续遇到错误（例如 wait-queue 等待超过 MaxWait），此时可通过 Err 观察到最近一次错误
+ */
+func NewBuffered(datacenterId, machineId int64, bufSize int, opts ...Option) (*BufferedSnowFlake, error) {
+	sf, err := New(datacenterId, machineId, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &BufferedSnowFlake{
+		sf:     sf,
+		ch:     make(chan int64, bufSize),
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	go b.produce()
+
+	return b, nil
+}
+
+/**
+ * produce 是后台生产者，不断向 channel 中填充预生成的 ID，直到 Close 被调用。调用的是
+ * NextIDSafe 而不是 NextID：时钟回拨策略返回 error 时（例如 wait-queue 超时）只记录
+ * 并退避重试，而不是 panic 杀死整个进程
+ */
+func (b *BufferedSnowFlake) produce() {
+	defer close(b.done)
+	for {
+		id, err := b.sf.NextIDSafe()
+		if err != nil {
+			b.setErr(err)
+			select {
+			case <-b.closed:
+				return
+			case <-time.After(produceRetryBackoff):
+				continue
+			}
+		}
+		b.setErr(nil)
+
+		select {
+		case b.ch <- id:
+		case <-b.closed:
+			return
+		}
+	}
+}
+
+/**
+ * setErr 记录后台生产者最近一次遇到的错误，供 Err 查询
+ */
+func (b *BufferedSnowFlake) setErr(err error) {
+	b.mu.Lock()
+	b.lastErr = err
+	b.mu.Unlock()
+}
+
+/**
+ * Err 返回后台生产者最近一次遇到的错误（成功生成新ID后会被清除），nil 表示目前运行正常
+ */
+func (b *BufferedSnowFlake) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastErr
+}
+
+/**
+ * NextID 从预生成的缓冲 channel 中取出下一个 ID，如果缓冲区暂时为空则阻塞等待。
+ */
+func (b *BufferedSnowFlake) NextID() int64 {
+	return <-b.ch
+}
+
+/**
+ * TryNextID 尝试从缓冲 channel 中非阻塞地取出下一个 ID，缓冲区为空时返回 false。
+ */
+func (b *BufferedSnowFlake) TryNextID() (int64, bool) {
+	select {
+	case id := <-b.ch:
+		return id, true
+	default:
+		return 0, false
+	}
+}
+
+/**
+ * Close 停止后台生产者 goroutine 并等待其退出，保证不会泄漏 goroutine。
+ */
+func (b *BufferedSnowFlake) Close() error {
+	close(b.closed)
+	<-b.done
+	return nil
+}
@@ -0,0 +1,134 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewBufferedThreadsOptions(t *testing.T) {
+	fc := NewFakeClock(10_000)
+	bsf, err := NewBuffered(2, 2, 4, WithClock(fc), WithClockBackwardStrategy(ExtensionBitStrategy{}))
+	if err != nil {
+		t.Fatalf("NewBuffered failed: %v", err)
+	}
+	defer bsf.Close()
+
+	if got := bsf.sf.BackwardStrategy(); got != "extension-bit" {
+		t.Fatalf("expected ExtensionBitStrategy to be threaded through, got %q", got)
+	}
+	if _, ok := bsf.sf.clock.(*FakeClock); !ok {
+		t.Fatalf("expected injected FakeClock to be threaded through, got %T", bsf.sf.clock)
+	}
+}
+
+func TestBufferedCloseDoesNotLeakGoroutine(t *testing.T) {
+	bsf, err := NewBuffered(1, 1, 4)
+	if err != nil {
+		t.Fatalf("NewBuffered failed: %v", err)
+	}
+	bsf.NextID()
+	if err := bsf.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	select {
+	case <-bsf.done:
+	default:
+		t.Fatal("expected produce() goroutine to have exited after Close")
+	}
+}
+
+func TestBufferedProduceSurvivesBackwardStrategyErrorWithoutPanicking(t *testing.T) {
+	fc := NewFakeClock(10_000)
+	bsf, err := NewBuffered(0, 0, 1, WithClock(fc), WithClockBackwardStrategy(NewWaitQueueStrategy(20*time.Millisecond)))
+	if err != nil {
+		t.Fatalf("NewBuffered failed: %v", err)
+	}
+	defer bsf.Close()
+
+	bsf.NextID()
+	fc.Set(9_000) // rollback the wait-queue strategy never recovers from
+
+	deadline := time.After(2 * time.Second)
+	for {
+		bsf.TryNextID() // keep draining so produce() isn't stuck blocked on a channel send
+		if err := bsf.Err(); err != nil {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("expected produce() to record an error from the stuck wait-queue strategy")
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	// produce() must still be alive (retrying), not dead from an unrecovered panic.
+	select {
+	case <-bsf.done:
+		t.Fatal("produce() exited, presumably from a panic, instead of retrying after the error")
+	default:
+	}
+}
+
+func TestBufferedErrClearsAfterRecovery(t *testing.T) {
+	fc := NewFakeClock(10_000)
+	bsf, err := NewBuffered(0, 0, 1, WithClock(fc), WithClockBackwardStrategy(NewWaitQueueStrategy(time.Second)))
+	if err != nil {
+		t.Fatalf("NewBuffered failed: %v", err)
+	}
+	defer bsf.Close()
+
+	bsf.NextID()
+	fc.Set(9_000) // rollback; wait-queue will retry until the clock catches up
+
+	deadline := time.Now().Add(2 * time.Second)
+	for bsf.Err() == nil {
+		bsf.TryNextID() // keep draining so produce() isn't stuck blocked on a channel send
+		if time.Now().After(deadline) {
+			t.Fatal("expected produce() to observe the rollback before recovering")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	fc.Set(10_050) // clock catches back up; produce() should recover
+
+	deadline = time.Now().Add(2 * time.Second)
+	for {
+		bsf.TryNextID()
+		if bsf.Err() == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected Err() to clear once produce() resumed successfully")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func BenchmarkNextID(b *testing.B) {
+	sf, err := New(1, 1)
+	if err != nil {
+		b.Fatalf("New failed: %v", err)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			sf.NextID()
+		}
+	})
+}
+
+func BenchmarkBufferedNextID(b *testing.B) {
+	bsf, err := NewBuffered(1, 1, 1024)
+	if err != nil {
+		b.Fatalf("NewBuffered failed: %v", err)
+	}
+	defer bsf.Close()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			bsf.NextID()
+		}
+	})
+}
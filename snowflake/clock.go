@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"sync"
+	"time"
+)
+
+/**
+ * Clock 是 SnowFlake 读取当前毫秒时间戳所依赖的时间源。默认实现基于带单调时钟的
+ * time.Time，测试中可以注入 FakeClock 以摆脱真实的 wall clock，从而在不 sleep 的情况下
+ * 测试时钟回拨、序列号耗尽等逻辑
+ */
+type Clock interface {
+	NowMs() int64
+}
+
+/**
+ * monotonicClock 是默认的 Clock 实现，与历史版本中 SnowFlake.GetNow 的行为一致：
+ * 基于 time.Since(epoch) 的单调时钟差值计算当前毫秒时间戳
+ */
+type monotonicClock struct {
+	epoch time.Time
+}
+
+func newMonotonicClock(epochMs int64) monotonicClock {
+	return newMonotonicClockAt(time.Unix(epochMs/1000, (epochMs%1000)*1000000))
+}
+
+/**
+ * newMonotonicClockAt 与 newMonotonicClock 等价，但直接接受一个纪元 time.Time，
+ * 供 NewWithConfig 按照自定义 Config.Epoch 构造时钟
+ */
+func newMonotonicClockAt(epochTime time.Time) monotonicClock {
+	now := time.Now() // 获取带有 单调时钟的 time
+	// 通过计算，获取带有 单调时钟的 纪元 time
+	epoch := now.Add(epochTime.Sub(now))
+	return monotonicClock{epoch: epoch}
+}
+
+func (c monotonicClock) NowMs() int64 {
+	return time.Since(c.epoch).Nanoseconds() / 1000000
+}
+
+/**
+ * FakeClock 是供测试使用的 Clock 实现，当前时间由调用方显式设置/推进，可以在不依赖
+ * 真实时间流逝的情况下模拟时钟正常前进、静止或回拨
+ */
+type FakeClock struct {
+	mu sync.Mutex
+	ms int64
+}
+
+/**
+ * NewFakeClock 创建一个起始时间为 startMs 的 FakeClock
+ */
+func NewFakeClock(startMs int64) *FakeClock {
+	return &FakeClock{ms: startMs}
+}
+
+func (f *FakeClock) NowMs() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ms
+}
+
+/**
+ * Advance 将时钟向前推进 d，d 为负数时等价于 Rewind，用于模拟时钟回拨
+ */
+func (f *FakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ms += d.Milliseconds()
+}
+
+/**
+ * Set 将时钟直接设置为指定的毫秒时间戳
+ */
+func (f *FakeClock) Set(ms int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ms = ms
+}
@@ -0,0 +1,85 @@
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+/**
+ * ClockBackwardStrategy 定义时钟回拨时 NextIDSafe 应该如何处理。HandleBackward 在检测到
+ * now < s.timestamp 时被调用（持有 s 的锁），now 为本次观测到的当前毫秒时间戳，返回值为
+ * 应当继续使用的时间戳；如果无法安全地继续生成ID，返回 error
+ */
+type ClockBackwardStrategy interface {
+	Name() string
+	HandleBackward(s *SnowFlake, now int64) (int64, error)
+}
+
+/**
+ * PanicOnBackwardStrategy 是默认策略：回拨在 MAX_BACKWARD_MS 容忍范围内时等待两倍偏移时间，
+ * 超出范围则返回 error（NextID 会将其转换为 panic），与历史行为保持一致
+ */
+type PanicOnBackwardStrategy struct{}
+
+func (PanicOnBackwardStrategy) Name() string {
+	return "panic-on-backward"
+}
+
+func (PanicOnBackwardStrategy) HandleBackward(s *SnowFlake, now int64) (int64, error) {
+	offset := s.timestamp - now
+	if offset <= MAX_BACKWARD_MS {
+		// 时间偏差在容忍范围内，则等待两倍时间
+		time.Sleep(time.Millisecond * time.Duration(offset<<1))
+		now = s.GetNow()
+		if now >= s.timestamp {
+			return now, nil
+		}
+	}
+
+	// 服务时钟被调整，ID生成器停止服务
+	return 0, fmt.Errorf("Clock moved backwards. Refusing to generate id for %d milliseconds", s.timestamp-now)
+}
+
+/**
+ * ExtensionBitStrategy 检测到回拨时不等待也不panic，而是将数据中心字段的最高位作为
+ * 回拨代数翻转，使得本代与上一代的ID空间不相交（时间戳相同也不会与回拨前的ID冲突），
+ * 然后继续正常生成
+ */
+type ExtensionBitStrategy struct{}
+
+func (ExtensionBitStrategy) Name() string {
+	return "extension-bit"
+}
+
+func (ExtensionBitStrategy) HandleBackward(s *SnowFlake, now int64) (int64, error) {
+	s.generation ^= 1
+	return now, nil
+}
+
+/**
+ * WaitQueueStrategy 让 NextIDSafe 阻塞，直到系统时钟追上 s.timestamp 为止，
+ * 超过 MaxWait 仍未追上则返回 error 而不是 panic
+ */
+type WaitQueueStrategy struct {
+	MaxWait time.Duration
+}
+
+func NewWaitQueueStrategy(maxWait time.Duration) WaitQueueStrategy {
+	return WaitQueueStrategy{MaxWait: maxWait}
+}
+
+func (WaitQueueStrategy) Name() string {
+	return "wait-queue"
+}
+
+func (w WaitQueueStrategy) HandleBackward(s *SnowFlake, now int64) (int64, error) {
+	deadline := time.Now().Add(w.MaxWait)
+	for now < s.timestamp {
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("clock did not catch up within %s, refusing to generate id", w.MaxWait)
+		}
+		time.Sleep(time.Millisecond)
+		now = s.GetNow()
+	}
+	return now, nil
+}
@@ -0,0 +1,103 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPanicOnBackwardStrategyPanicsBeyondTolerance(t *testing.T) {
+	fc := NewFakeClock(10_000)
+	sf, err := New(0, 0, WithClock(fc))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	sf.NextID()
+	fc.Set(9_000) // rollback far beyond MAX_BACKWARD_MS
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected NextID to panic when the clock rolls back beyond tolerance")
+		}
+	}()
+	sf.NextID()
+}
+
+func TestExtensionBitStrategyContinuesAcrossRollback(t *testing.T) {
+	fc := NewFakeClock(10_000)
+	sf, err := New(1, 1, WithClock(fc), WithClockBackwardStrategy(ExtensionBitStrategy{}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	id1 := sf.NextID()
+	fc.Set(5_000) // rollback far beyond tolerance
+	id2 := sf.NextID()
+
+	if sf.BackwardStrategy() != "extension-bit" {
+		t.Fatalf("expected extension-bit strategy, got %q", sf.BackwardStrategy())
+	}
+	if id1 == id2 {
+		t.Fatal("expected a distinct ID after rollback")
+	}
+
+	c1, c2 := Parse(id1), Parse(id2)
+	if c1.DatacenterID == c2.DatacenterID {
+		t.Fatalf("expected the rollback generation bit to change the datacenter field, got %d both times", c1.DatacenterID)
+	}
+}
+
+func TestExtensionBitStrategyRejectsDatacenterIdUsingTopBit(t *testing.T) {
+	// Default layout has 5 datacenter bits (max 31); the top bit (16) is reserved
+	// for the rollback generation, so datacenterId must stay below 16.
+	if _, err := New(31, 0, WithClockBackwardStrategy(ExtensionBitStrategy{})); err == nil {
+		t.Fatal("expected New to reject a datacenterId that already uses the generation bit")
+	}
+	if _, err := New(16, 0, WithClockBackwardStrategy(ExtensionBitStrategy{})); err == nil {
+		t.Fatal("expected New to reject datacenterId at the generation-bit boundary")
+	}
+	if _, err := New(15, 0, WithClockBackwardStrategy(ExtensionBitStrategy{})); err != nil {
+		t.Fatalf("expected New to accept datacenterId below the generation-bit boundary, got %v", err)
+	}
+}
+
+func TestWaitQueueStrategyErrorsWhenClockNeverCatchesUp(t *testing.T) {
+	fc := NewFakeClock(10_000)
+	sf, err := New(0, 0, WithClock(fc), WithClockBackwardStrategy(NewWaitQueueStrategy(20*time.Millisecond)))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	sf.NextID()
+	fc.Set(9_000) // rollback that the fake clock never recovers from
+
+	if _, err := sf.NextIDSafe(); err == nil {
+		t.Fatal("expected NextIDSafe to return an error instead of panicking")
+	}
+}
+
+func TestWaitQueueStrategyResumesOnceClockCatchesUp(t *testing.T) {
+	fc := NewFakeClock(10_000)
+	sf, err := New(0, 0, WithClock(fc), WithClockBackwardStrategy(NewWaitQueueStrategy(time.Second)))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	sf.NextID()
+	fc.Set(9_000)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := sf.NextIDSafe()
+		result <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	fc.Set(10_050) // clock catches back up; NextIDSafe should unblock
+
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatalf("expected NextIDSafe to resume without error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("NextIDSafe did not resume after the clock caught up")
+	}
+}
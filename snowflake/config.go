@@ -0,0 +1,59 @@
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+/**
+ * Config 描述一个 SnowFlake 实例的位布局：时间戳/数据中心/工作节点/序列号各占多少位，
+ * 以及起始纪元时间。四段位宽之和必须为63（留出最高的符号位不使用），这样每个部署可以
+ * 根据自己的吞吐量、节点规模或者使用年限灵活分配位宽，而不必都采用41/5/5/12这一种布局
+ */
+type Config struct {
+	Epoch          time.Time
+	TimestampBits  uint
+	DatacenterBits uint
+	WorkerBits     uint
+	SequenceBits   uint
+}
+
+/**
+ * DefaultConfig 返回与历史版本完全一致的布局：41位时间戳、5位数据中心、5位工作节点、
+ * 12位序列号，起始纪元为2020-01-01 00:00:00
+ */
+func DefaultConfig() Config {
+	return Config{
+		Epoch:          time.Unix(EPOCH/1000, (EPOCH%1000)*1000000),
+		TimestampBits:  BIT_TIMESTAMP,
+		DatacenterBits: BIT_DATACENTER,
+		WorkerBits:     BIT_WORKER,
+		SequenceBits:   BIT_SEQUENCE,
+	}
+}
+
+func (cfg Config) validate() error {
+	total := cfg.TimestampBits + cfg.DatacenterBits + cfg.WorkerBits + cfg.SequenceBits
+	if total != 63 {
+		return fmt.Errorf("snowflake: TimestampBits+DatacenterBits+WorkerBits+SequenceBits must sum to 63, got %d", total)
+	}
+	return nil
+}
+
+func (cfg Config) maxTimestamp() int64  { return -1 ^ (-1 << cfg.TimestampBits) }
+func (cfg Config) maxDatacenter() int64 { return -1 ^ (-1 << cfg.DatacenterBits) }
+func (cfg Config) maxWorker() int64     { return -1 ^ (-1 << cfg.WorkerBits) }
+func (cfg Config) maxSequence() int64   { return -1 ^ (-1 << cfg.SequenceBits) }
+
+func (cfg Config) shiftWorker() uint     { return cfg.SequenceBits }
+func (cfg Config) shiftDatacenter() uint { return cfg.SequenceBits + cfg.WorkerBits }
+func (cfg Config) shiftTimestamp() uint {
+	return cfg.SequenceBits + cfg.WorkerBits + cfg.DatacenterBits
+}
+
+/**
+ * epochMs 以Unix毫秒表示该布局的起始纪元，供 Parse 还原绝对时间使用
+ */
+func (cfg Config) epochMs() int64 {
+	return cfg.Epoch.UnixNano() / 1000000
+}
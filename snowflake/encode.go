@@ -0,0 +1,222 @@
+package snowflake
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// encodeBase32Map、decodeBase32Map 用于 ID 的 Base32 编解码，字母表排除了容易混淆的字符
+const encodeBase32Map = "ybndrfg8ejkmcpqxot1uwisza345h769"
+
+var decodeBase32Map [256]byte
+
+// encodeBase58Map、decodeBase58Map 用于 ID 的 Base58 编解码，同样排除了容易混淆的字符
+const encodeBase58Map = "123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+
+var decodeBase58Map [256]byte
+
+func init() {
+	for i := 0; i < len(decodeBase32Map); i++ {
+		decodeBase32Map[i] = 0xFF
+	}
+	for i := 0; i < len(encodeBase32Map); i++ {
+		decodeBase32Map[encodeBase32Map[i]] = byte(i)
+	}
+
+	for i := 0; i < len(decodeBase58Map); i++ {
+		decodeBase58Map[i] = 0xFF
+	}
+	for i := 0; i < len(encodeBase58Map); i++ {
+		decodeBase58Map[encodeBase58Map[i]] = byte(i)
+	}
+}
+
+/**
+ * String 返回ID的十进制字符串表示，这是日志、URL、JSON等文本场景下最常用的形式
+ */
+func (id ID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+/**
+ * Base2 返回ID的二进制字符串表示
+ */
+func (id ID) Base2() string {
+	return strconv.FormatInt(int64(id), 2)
+}
+
+/**
+ * Base32 使用排除了易混淆字符的字母表对ID进行编码
+ */
+func (id ID) Base32() string {
+	if id < 0 {
+		return ""
+	}
+
+	b := make([]byte, 0, 12)
+	v := uint64(id)
+	for v >= 32 {
+		b = append(b, encodeBase32Map[v%32])
+		v /= 32
+	}
+	b = append(b, encodeBase32Map[v])
+
+	for x, y := 0, len(b)-1; x < y; x, y = x+1, y-1 {
+		b[x], b[y] = b[y], b[x]
+	}
+
+	return string(b)
+}
+
+/**
+ * Base58 使用比特币风格的字母表对ID进行编码
+ */
+func (id ID) Base58() string {
+	if id < 0 {
+		return ""
+	}
+
+	b := make([]byte, 0, 11)
+	v := uint64(id)
+	for v >= 58 {
+		b = append(b, encodeBase58Map[v%58])
+		v /= 58
+	}
+	b = append(b, encodeBase58Map[v])
+
+	for x, y := 0, len(b)-1; x < y; x, y = x+1, y-1 {
+		b[x], b[y] = b[y], b[x]
+	}
+
+	return string(b)
+}
+
+/**
+ * Bytes 返回ID十进制字符串表示对应的字节切片
+ */
+func (id ID) Bytes() []byte {
+	return []byte(id.String())
+}
+
+/**
+ * Base64 返回对ID十进制字符串编码之后的标准 Base64 字符串
+ */
+func (id ID) Base64() string {
+	return base64.StdEncoding.EncodeToString(id.Bytes())
+}
+
+/**
+ * ParseString 将十进制字符串解析为ID
+ */
+func ParseString(s string) (ID, error) {
+	i, err := strconv.ParseInt(s, 10, 64)
+	return ID(i), err
+}
+
+/**
+ * ParseBase2 将二进制字符串解析为ID
+ */
+func ParseBase2(s string) (ID, error) {
+	i, err := strconv.ParseInt(s, 2, 64)
+	return ID(i), err
+}
+
+/**
+ * ParseBase32 将 Base32 字符串解析为ID
+ */
+func ParseBase32(s string) (ID, error) {
+	var id int64
+	for i := 0; i < len(s); i++ {
+		b := decodeBase32Map[s[i]]
+		if b == 0xFF {
+			return 0, fmt.Errorf("snowflake: invalid base32 character %q", s[i])
+		}
+		id = id*32 + int64(b)
+	}
+	return ID(id), nil
+}
+
+/**
+ * ParseBase58 将 Base58 字符串解析为ID
+ */
+func ParseBase58(s string) (ID, error) {
+	var id int64
+	for i := 0; i < len(s); i++ {
+		b := decodeBase58Map[s[i]]
+		if b == 0xFF {
+			return 0, fmt.Errorf("snowflake: invalid base58 character %q", s[i])
+		}
+		id = id*58 + int64(b)
+	}
+	return ID(id), nil
+}
+
+/**
+ * ParseBytes 将 Bytes 方法产生的字节切片解析为ID
+ */
+func ParseBytes(b []byte) (ID, error) {
+	i, err := strconv.ParseInt(string(b), 10, 64)
+	return ID(i), err
+}
+
+/**
+ * ParseBase64 将 Base64 方法产生的字符串解析为ID
+ */
+func ParseBase64(s string) (ID, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+	return ParseBytes(b)
+}
+
+/**
+ * MarshalJSON 将ID序列化为带引号的十进制字符串，避免64位整数在JavaScript等
+ * 只有双精度浮点数的消费方那里丢失精度
+ */
+func (id ID) MarshalJSON() ([]byte, error) {
+	buf := make([]byte, 0, 22)
+	buf = append(buf, '"')
+	buf = strconv.AppendInt(buf, int64(id), 10)
+	buf = append(buf, '"')
+	return buf, nil
+}
+
+/**
+ * UnmarshalJSON 从带引号的十进制字符串（或裸数字，兼容旧数据）反序列化出ID
+ */
+func (id *ID) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*id = ID(i)
+	return nil
+}
+
+/**
+ * MarshalBinary 将ID编码为8字节大端序二进制数据，用于对体积敏感的传输场景
+ */
+func (id ID) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(id))
+	return b, nil
+}
+
+/**
+ * UnmarshalBinary 从 MarshalBinary 产生的8字节大端序数据中还原ID
+ */
+func (id *ID) UnmarshalBinary(b []byte) error {
+	if len(b) != 8 {
+		return fmt.Errorf("snowflake: invalid binary ID length %d, want 8", len(b))
+	}
+	*id = ID(binary.BigEndian.Uint64(b))
+	return nil
+}
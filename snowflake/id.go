@@ -0,0 +1,27 @@
+package snowflake
+
+/**
+ * ID 是 NextID 产生的64位整型ID的具名类型，挂载了 String/Base32/Base58/Base64 等编码
+ * 方法（见 encode.go），方便直接对外暴露编码格式而不必手动转换。
+ *
+ * 注意：ID 本身不携带生成它的 SnowFlake 实例的位布局/起始纪元，因此没有提供
+ * Time/Node/Step 这类需要反解出时间戳、数据中心ID等组件的方法——包级别的 Parse
+ * 只能假定默认布局，对 NewWithConfig 自定义布局产生的ID会解码出错误的结果。要反解
+ * 一个ID的组成部分，请使用生成它的那个 *SnowFlake 实例的 Parse 方法
+ */
+type ID int64
+
+/**
+ * Int64 返回底层的 int64 值
+ */
+func (id ID) Int64() int64 {
+	return int64(id)
+}
+
+/**
+ * NextIDTyped 与 NextID 等价，但返回挂载了 String/Base32/Base58/Base64 等编码方法的
+ * ID 类型，适合直接用在需要对外暴露编码格式的场景（web API、日志等）
+ */
+func (s *SnowFlake) NextIDTyped() ID {
+	return ID(s.NextID())
+}
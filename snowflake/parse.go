@@ -0,0 +1,30 @@
+package snowflake
+
+import "time"
+
+/**
+ * Components 是对一个生成出的ID做反解之后的各个组成部分
+ */
+type Components struct {
+	Timestamp    time.Time
+	DatacenterID int64
+	WorkerID     int64
+	Sequence     int64
+}
+
+/**
+ * Parse 将一个已经生成的ID反解为时间戳、数据中心ID、工作节点ID和序列号。
+ * 按照包级别的默认位布局（EPOCH、BIT_TIMESTAMP 等常量）解析，解析 NewWithConfig
+ * 生成的自定义位布局ID时请使用对应 SnowFlake 实例的 Parse 方法
+ */
+func Parse(id int64) Components {
+	timestampOffset := id >> SHIFT_TIMESTAMP
+	ms := EPOCH + timestampOffset
+
+	return Components{
+		Timestamp:    time.Unix(ms/1000, (ms%1000)*1000000),
+		DatacenterID: (id >> SHIFT_DATACENTER) & MAX_DATACENTER_ID,
+		WorkerID:     (id >> SHIFT_WORKER) & MAX_WORKER_ID,
+		Sequence:     id & MAX_SEQUENCE_ID,
+	}
+}
@@ -0,0 +1,93 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRoundTripsComponents(t *testing.T) {
+	fc := NewFakeClock(123_456)
+	sf, err := New(7, 9, WithClock(fc))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	id := sf.NextID()
+	c := Parse(id)
+	if c.DatacenterID != 7 {
+		t.Errorf("DatacenterID = %d, want 7", c.DatacenterID)
+	}
+	if c.WorkerID != 9 {
+		t.Errorf("WorkerID = %d, want 9", c.WorkerID)
+	}
+	if c.Sequence != 0 {
+		t.Errorf("Sequence = %d, want 0", c.Sequence)
+	}
+}
+
+func TestNextIDTypedEncodingsRoundTrip(t *testing.T) {
+	fc := NewFakeClock(123_456)
+	sf, err := New(3, 4, WithClock(fc))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	id := sf.NextIDTyped()
+	if got, err := ParseString(id.String()); err != nil || got != id {
+		t.Errorf("ParseString(%q) = (%d, %v), want (%d, nil)", id.String(), got, err, id)
+	}
+}
+
+func TestInstanceParseUsesOwnConfigNotDefaultLayout(t *testing.T) {
+	// A layout that differs from DefaultConfig() in every field, so decoding it
+	// against the package-level default constants would produce wrong results.
+	cfg := Config{
+		Epoch:          time.Unix(0, 0),
+		TimestampBits:  41,
+		DatacenterBits: 3,
+		WorkerBits:     3,
+		SequenceBits:   16,
+	}
+	sf, err := NewWithConfig(cfg, 2, 2)
+	if err != nil {
+		t.Fatalf("NewWithConfig failed: %v", err)
+	}
+
+	id := sf.NextID()
+	c := sf.Parse(id)
+	if c.DatacenterID != 2 {
+		t.Errorf("DatacenterID = %d, want 2", c.DatacenterID)
+	}
+	if c.WorkerID != 2 {
+		t.Errorf("WorkerID = %d, want 2", c.WorkerID)
+	}
+
+	// Decoding the same raw ID against the package-level default layout (41/5/5/12)
+	// must not be mistaken for the instance's own correct 41/3/3/16 decode (2, 2).
+	defaultDecoded := Parse(id)
+	if defaultDecoded.DatacenterID == 2 && defaultDecoded.WorkerID == 2 {
+		t.Fatal("expected default-layout Parse to disagree with the instance's non-default Config decode")
+	}
+}
+
+func TestFakeClockAdvanceAndSet(t *testing.T) {
+	fc := NewFakeClock(1_000)
+	if got := fc.NowMs(); got != 1_000 {
+		t.Fatalf("NowMs() = %d, want 1000", got)
+	}
+
+	fc.Advance(500 * time.Millisecond)
+	if got := fc.NowMs(); got != 1_500 {
+		t.Fatalf("NowMs() after Advance = %d, want 1500", got)
+	}
+
+	fc.Advance(-2 * time.Second) // simulate a clock rollback
+	if got := fc.NowMs(); got != -500 {
+		t.Fatalf("NowMs() after rollback Advance = %d, want -500", got)
+	}
+
+	fc.Set(42)
+	if got := fc.NowMs(); got != 42 {
+		t.Fatalf("NowMs() after Set = %d, want 42", got)
+	}
+}
@@ -38,69 +38,143 @@ const (
 
 type SnowFlake struct {
 	sync.Mutex
-	epoch        time.Time
+	cfg          Config
+	clock        Clock
 	timestamp    int64
 	datacenterId int64
 	workerId     int64
 	sequence     int64
+	backward     ClockBackwardStrategy
+	generation   int64 // 时钟回拨代数，由 extension-bit 策略维护，借用数据中心字段的最高位
 }
 
-func New(datacenterId, machineId int64) (*SnowFlake, error) {
-	if datacenterId < 0 || datacenterId > MAX_DATACENTER_ID {
-		return nil, fmt.Errorf("datacenterId must be between 0 and %d", MAX_DATACENTER_ID-1)
+// Option 用于在 New 时对 SnowFlake 做可选配置
+type Option func(*SnowFlake)
+
+/**
+ * WithClockBackwardStrategy 指定时钟回拨时的处理策略，未指定时默认为 panic-on-backward
+ * （即当前的短暂等待后 panic 的行为）
+ */
+func WithClockBackwardStrategy(strategy ClockBackwardStrategy) Option {
+	return func(s *SnowFlake) {
+		s.backward = strategy
+	}
+}
+
+/**
+ * WithClock 注入自定义的时间源，主要用于测试中注入 FakeClock，
+ * 使回拨、序列号耗尽等逻辑无需真实 sleep 即可验证
+ */
+func WithClock(clock Clock) Option {
+	return func(s *SnowFlake) {
+		s.clock = clock
+	}
+}
+
+func New(datacenterId, machineId int64, opts ...Option) (*SnowFlake, error) {
+	return NewWithConfig(DefaultConfig(), datacenterId, machineId, opts...)
+}
+
+/**
+ * NewWithConfig 按照 cfg 描述的位布局创建一个 SnowFlake 实例。cfg 的四段位宽之和必须
+ * 为63，datacenterId/machineId 的合法范围由 cfg 中对应的位宽决定，而不再是固定的
+ * 0~31。这支持更高吞吐（更多序列号位）、更大集群（更多工作节点位）或者更长使用年限
+ * （更多时间戳位、自定义起始纪元）等部署需求
+ */
+func NewWithConfig(cfg Config, datacenterId, machineId int64, opts ...Option) (*SnowFlake, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
 	}
-	if machineId < 0 || machineId > MAX_WORKER_ID {
-		return nil, fmt.Errorf("machineId must be between 0 and %d", MAX_WORKER_ID-1)
+	if cfg.Epoch.IsZero() {
+		cfg.Epoch = time.Unix(EPOCH/1000, (EPOCH%1000)*1000000)
 	}
 
-	now := time.Now() // 获取带有 单调时钟的 time
-	// 通过计算，获取带有 单调时钟的 纪元 time
-	epoch := now.Add(time.Unix(EPOCH/1000, (EPOCH%1000)*1000000).Sub(now))
-	return &SnowFlake{
-		epoch:        epoch,
+	maxDatacenterId := cfg.maxDatacenter()
+	maxWorkerId := cfg.maxWorker()
+
+	if datacenterId < 0 || datacenterId > maxDatacenterId {
+		return nil, fmt.Errorf("datacenterId must be between 0 and %d", maxDatacenterId-1)
+	}
+	if machineId < 0 || machineId > maxWorkerId {
+		return nil, fmt.Errorf("machineId must be between 0 and %d", maxWorkerId-1)
+	}
+
+	s := &SnowFlake{
+		cfg:          cfg,
+		clock:        newMonotonicClockAt(cfg.Epoch),
 		timestamp:    0,
 		datacenterId: datacenterId,
 		workerId:     machineId,
 		sequence:     0,
-	}, nil
+		backward:     PanicOnBackwardStrategy{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// ExtensionBitStrategy 借用数据中心字段的最高位记录回拨代数，如果 datacenterId 本身
+	// 已经用到了这一位，代数翻转就是空操作，起不到让新老两代ID空间不相交的作用，所以在
+	// 这种组合下直接拒绝构造，而不是静默产生可能冲突的ID
+	if _, ok := s.backward.(ExtensionBitStrategy); ok {
+		limit := s.cfg.maxDatacenter()/2 + 1
+		if datacenterId >= limit {
+			return nil, fmt.Errorf("datacenterId must be less than %d to use ExtensionBitStrategy (top bit of the datacenter field is reserved for the rollback generation)", limit)
+		}
+	}
+
+	return s, nil
 }
 
 /**
- * 产生下一个ID
+ * BackwardStrategy 返回当前生效的时钟回拨策略名称
+ */
+func (s *SnowFlake) BackwardStrategy() string {
+	return s.backward.Name()
+}
+
+/**
+ * 产生下一个ID，时钟回拨超出容忍范围时 panic。内部委托给 NextIDSafe，
+ * 等价于历史版本中 panic-on-backward 的行为，用于兼容旧的调用方式
  */
 func (s *SnowFlake) NextID() int64 {
+	id, err := s.NextIDSafe()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+/**
+ * NextIDSafe 产生下一个ID，时钟回拨时按照配置的 ClockBackwardStrategy 处理，
+ * 策略拒绝继续生成时返回 error 而不是 panic（wait-queue 模式即依赖这一点）
+ */
+func (s *SnowFlake) NextIDSafe() (int64, error) {
 	s.Lock()
 	defer s.Unlock()
+	return s.nextLocked()
+}
+
+/**
+ * nextLocked 是生成单个ID的核心逻辑，调用方必须已持有 s 的锁。NextIDSafe 和批量生成的
+ * NextIDs 都基于它实现，以保证二者在时钟回拨、序列号耗尽等边界情况下行为完全一致
+ */
+func (s *SnowFlake) nextLocked() (int64, error) {
 	now := s.GetNow() // 转毫秒
 
-	// 发生时钟回拨
+	// 发生时钟回拨，交给配置的策略处理
 	if now < s.timestamp {
-		isOverBackWard := false
-		offset := s.timestamp - now
-		if offset <= MAX_BACKWARD_MS { // 时钟回拨在可接受范围内，等待即可
-			// 时间偏差小于5ms，则等待两倍时间
-			time.Sleep(time.Millisecond * time.Duration(offset<<1))
-
-			now = s.GetNow()
-			// 如果时间还小于当前时间，则标记为超过时钟回拨范围
-			if now < s.timestamp {
-				isOverBackWard = true
-			}
-		} else {
-			isOverBackWard = true
-		}
-
-		// 如果时钟回拨超出可接受范围，则直接利用扩展字段
-		if isOverBackWard == true {
-			// 服务时钟被调整，ID生成器停止服务
-			panic(fmt.Errorf("Clock moved backwards. Refusing to generate id for %d milliseconds", s.timestamp-now))
+		newNow, err := s.backward.HandleBackward(s, now)
+		if err != nil {
+			return 0, err
 		}
+		now = newNow
 	}
 
 	// 如果和最后一次请求处于同一毫秒，则sequence++
 	if s.timestamp == now {
 		// 当同一时间戳（精度：毫秒）下多次生成id会增加序列号
-		s.sequence = (s.sequence + 1) & MAX_SEQUENCE_ID
+		s.sequence = (s.sequence + 1) & s.cfg.maxSequence()
 		if s.sequence == 0 { // 同一毫秒的序列数已经达到最大
 			for now <= s.timestamp {
 				now = s.GetNow()
@@ -114,29 +188,62 @@ func (s *SnowFlake) NextID() int64 {
 	// 更新上一次生成ID的时间戳
 	s.timestamp = now
 
-	// 进行位移操作，生成int64的唯一ID
-	//t := (now - EPOCH) << SHIFT_TIMESTAMP
+	return s.encode(now), nil
+}
 
-	return int64((now-EPOCH)<<SHIFT_TIMESTAMP | // 时间戳
-		s.datacenterId<<SHIFT_DATACENTER | // 数据中心
-		s.workerId<<SHIFT_WORKER | // 机器标识
+/**
+ * encode 按照该实例的位布局把 now（相对于 s.cfg.Epoch 的毫秒偏移量）、数据中心、
+ * 工作节点、当前序列号拼装成最终的int64 ID。调用方必须已持有 s 的锁，且已经把
+ * s.sequence 推进到本次要使用的值；nextLocked 和批量生成的 NextIDs 共用这一步，
+ * 以保证两者产生的ID布局完全一致
+ */
+func (s *SnowFlake) encode(now int64) int64 {
+	return int64(now<<s.cfg.shiftTimestamp() | // 时间戳
+		s.datacenterField()<<s.cfg.shiftDatacenter() | // 数据中心（含 extension-bit 代数）
+		s.workerId<<s.cfg.shiftWorker() | // 机器标识
 		s.sequence, // 序列号
 	)
 }
 
+/**
+ * datacenterField 返回实际写入ID的数据中心字段。extension-bit 策略下，该字段的
+ * 最高位被用作时钟回拨代数，此时 datacenterId 必须小于该布局下数据中心最大值的一半，
+ * 以免与代数位冲突
+ */
+func (s *SnowFlake) datacenterField() int64 {
+	if _, ok := s.backward.(ExtensionBitStrategy); ok {
+		return s.datacenterId | (s.generation << (s.cfg.DatacenterBits - 1))
+	}
+	return s.datacenterId
+}
+
+/**
+ * Parse 按照该实例的位布局反解一个ID，用于自定义 Config 场景下的还原；
+ * 默认布局也可以直接使用包级别的 Parse 函数
+ */
+func (s *SnowFlake) Parse(id int64) Components {
+	timestampOffset := id >> s.cfg.shiftTimestamp()
+	ms := s.cfg.epochMs() + timestampOffset
+
+	return Components{
+		Timestamp:    time.Unix(ms/1000, (ms%1000)*1000000),
+		DatacenterID: (id >> s.cfg.shiftDatacenter()) & s.cfg.maxDatacenter(),
+		WorkerID:     (id >> s.cfg.shiftWorker()) & s.cfg.maxWorker(),
+		Sequence:     id & s.cfg.maxSequence(),
+	}
+}
+
 /**
  * 获取当前时间戳
  */
 func (s *SnowFlake) GetNow() int64 {
-	return time.Since(s.epoch).Nanoseconds() / 1000000
-	// return time.Now().UnixNano() / 1000000
+	return s.clock.NowMs()
 }
 
 /**
- * 获取数据中心ID和机器ID
+ * 获取数据中心ID和机器ID，保留用于兼容历史调用方式，内部委托给 Parse
  */
 func GetDeviceID(sid int64) (datacenterId, machineId int64) {
-	datacenterId = (sid >> SHIFT_DATACENTER) & MAX_DATACENTER_ID
-	machineId = (sid >> SHIFT_WORKER) & MAX_WORKER_ID
-	return
+	c := Parse(sid)
+	return c.DatacenterID, c.WorkerID
 }